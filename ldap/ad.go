@@ -0,0 +1,116 @@
+package ldap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// Active Directory userAccountControl bit flags.
+// See: https://learn.microsoft.com/en-us/troubleshoot/windows-server/identity/useraccountcontrol-manipulate-account-properties
+const (
+	uacAccountDisabled = 0x0002
+	uacLockout         = 0x0010
+	uacPasswordExpired = 0x800000
+)
+
+// userAccountControlAttr is the constructed AD attribute that carries the
+// bitmask decoded by ParseUserAccountControl.
+const userAccountControlAttr = "userAccountControl"
+
+// UserAccountControl holds the subset of the AD userAccountControl bitmask
+// that callers typically need to check before issuing a token.
+type UserAccountControl struct {
+	// Disabled is true when the ACCOUNTDISABLE bit is set.
+	Disabled bool
+
+	// Locked is true when the LOCKOUT bit is set.
+	Locked bool
+
+	// PasswordExpired is true when the PASSWORD_EXPIRED bit is set.
+	PasswordExpired bool
+}
+
+// ParseUserAccountControl decodes the raw userAccountControl attribute value
+// returned by an Active Directory search into a UserAccountControl.
+func ParseUserAccountControl(raw string) (*UserAccountControl, error) {
+	const op = "ldap.ParseUserAccountControl"
+	var v int64
+	if _, err := fmt.Sscanf(raw, "%d", &v); err != nil {
+		return nil, fmt.Errorf("%s: invalid userAccountControl value %q: %w", op, raw, ErrInvalidParameter)
+	}
+	return &UserAccountControl{
+		Disabled:        v&uacAccountDisabled != 0,
+		Locked:          v&uacLockout != 0,
+		PasswordExpired: v&uacPasswordExpired != 0,
+	}, nil
+}
+
+// adLoginFilter builds a filter that matches login against either
+// sAMAccountName or userPrincipalName, so a single configured UPNDomain can
+// authenticate users who type either form of their name. login and
+// upnDomain are escaped with ldap.EscapeFilter since login is
+// attacker-controlled input.
+func adLoginFilter(login, upnDomain string) string {
+	login = strings.TrimSuffix(login, "@"+upnDomain)
+	login = ldap.EscapeFilter(login)
+	upnDomain = ldap.EscapeFilter(upnDomain)
+	return fmt.Sprintf("(|(sAMAccountName=%s)(userPrincipalName=%s@%s))", login, login, upnDomain)
+}
+
+// nestedGroupFilter builds an AD LDAP_MATCHING_RULE_IN_CHAIN filter that
+// resolves nested group membership for userDN. It's used in ADMode when
+// UseTokenGroups is false, e.g. when bound to a non-global-catalog port
+// where tokenGroups is unavailable; whether tokenGroups is available is not
+// detected at runtime.
+func nestedGroupFilter(userDN string) string {
+	return fmt.Sprintf("(member:1.2.840.113556.1.4.1941:=%s)", ldap.EscapeFilter(userDN))
+}
+
+// encodeADPassword quotes and UTF-16LE encodes password the way Active
+// Directory requires for the unicodePwd attribute.
+func encodeADPassword(password string) []byte {
+	quoted := utf16.Encode([]rune("\"" + password + "\""))
+	encoded := make([]byte, len(quoted)*2)
+	for i, r := range quoted {
+		encoded[i*2] = byte(r & 0xff)
+		encoded[i*2+1] = byte(r >> 8)
+	}
+	return encoded
+}
+
+// ChangePassword performs an Active Directory password change for userDN by
+// replacing the unicodePwd attribute with a delete-then-add modify request,
+// as required by AD (a plain Replace is rejected). The connection must
+// already be established over LDAPS; AD refuses unicodePwd modifications
+// over an unencrypted connection.
+func (c *Client) ChangePassword(ctx context.Context, userDN, oldPassword, newPassword string) error {
+	const op = "ldap.(Client).ChangePassword"
+	if userDN == "" {
+		return fmt.Errorf("%s: missing userDN: %w", op, ErrInvalidParameter)
+	}
+	if oldPassword == "" || newPassword == "" {
+		return fmt.Errorf("%s: old and new passwords are required: %w", op, ErrInvalidParameter)
+	}
+
+	conn, err := c.getConnection(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer conn.Close()
+
+	if err := conn.Bind(c.config.BindDN, c.config.BindPassword); err != nil {
+		return fmt.Errorf("%s: unable to bind: %w", op, err)
+	}
+
+	req := ldap.NewModifyRequest(userDN, nil)
+	req.Delete("unicodePwd", []string{string(encodeADPassword(oldPassword))})
+	req.Add("unicodePwd", []string{string(encodeADPassword(newPassword))})
+	if err := conn.Modify(req); err != nil {
+		return fmt.Errorf("%s: unable to change password: %w", op, err)
+	}
+	return nil
+}
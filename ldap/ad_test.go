@@ -0,0 +1,77 @@
+package ldap
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseUserAccountControl(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    *UserAccountControl
+		wantErr bool
+	}{
+		{name: "normal enabled account", raw: "512", want: &UserAccountControl{}},
+		{name: "disabled", raw: "514", want: &UserAccountControl{Disabled: true}},
+		{name: "locked", raw: "528", want: &UserAccountControl{Locked: true}},
+		{name: "password expired", raw: "8389120", want: &UserAccountControl{PasswordExpired: true}},
+		{name: "not a number", raw: "nope", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseUserAccountControl(tt.raw)
+			if tt.wantErr {
+				if err == nil || !errors.Is(err, ErrInvalidParameter) {
+					t.Fatalf("expected ErrInvalidParameter, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if *got != *tt.want {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdLoginFilter_EscapesLogin(t *testing.T) {
+	malicious := "admin)(&(objectClass=*"
+	filter := adLoginFilter(malicious, "example.com")
+
+	if strings.Contains(filter, "admin)(&(objectClass=*") {
+		t.Fatalf("expected login to be escaped, got unescaped filter: %s", filter)
+	}
+	if !strings.Contains(filter, `\28`) || !strings.Contains(filter, `\2a`) {
+		t.Fatalf("expected parens/asterisk to be escaped in filter: %s", filter)
+	}
+}
+
+func TestAdLoginFilter_StripsUPNSuffix(t *testing.T) {
+	filter := adLoginFilter("alice@example.com", "example.com")
+	if strings.Count(filter, "alice@example.com") != 1 {
+		t.Fatalf("expected the UPN suffix to appear once (not doubled), got: %s", filter)
+	}
+}
+
+func TestNestedGroupFilter_EscapesUserDN(t *testing.T) {
+	filter := nestedGroupFilter("cn=alice)(objectClass=*,dc=example,dc=com")
+	if strings.Contains(filter, "cn=alice)(objectClass=*,dc=example,dc=com") {
+		t.Fatalf("expected userDN to be escaped, got: %s", filter)
+	}
+}
+
+func TestEncodeADPassword(t *testing.T) {
+	encoded := encodeADPassword("hi")
+	// `"hi"` UTF-16LE encoded is 8 bytes: 4 runes * 2 bytes each.
+	if len(encoded) != 8 {
+		t.Fatalf("expected 8 encoded bytes, got %d", len(encoded))
+	}
+	// First two bytes are the opening quote (0x22, 0x00) in UTF-16LE.
+	if encoded[0] != 0x22 || encoded[1] != 0x00 {
+		t.Fatalf("expected encoding to start with an opening quote, got % x", encoded[:2])
+	}
+}
@@ -0,0 +1,395 @@
+package ldap
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// Client connects to an LDAP directory server, authenticates users, and
+// resolves their group membership using a ClientConfig.
+type Client struct {
+	config *ClientConfig
+	pool   *Pool
+
+	closeOnce sync.Once
+	cancel    context.CancelFunc
+}
+
+// NewClient creates a Client from config. config is cloned and validated;
+// subsequent mutation of the original config has no effect on the Client.
+// Connections are dialed through a Pool, sized and tuned by config's
+// PoolMaxIdlePerHost/PoolMaxOpenPerHost/PoolWeighted/backoff fields, so that
+// repeated Authenticate and search calls reuse persistent connections per
+// URL and fail over between replicas instead of reconnecting on every call.
+// NewClient also starts a background goroutine that runs the pool's health
+// check every PoolHealthCheckIntervalSeconds; call Client.Close to stop it.
+func NewClient(ctx context.Context, config *ClientConfig) (*Client, error) {
+	const op = "ldap.NewClient"
+	if config == nil {
+		return nil, fmt.Errorf("%s: missing config: %w", op, ErrInvalidParameter)
+	}
+	clone, err := config.clone()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := clone.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	c := &Client{config: clone}
+	poolConfig := &PoolConfig{
+		Dial:                c.dial,
+		MaxIdlePerHost:      clone.PoolMaxIdlePerHost,
+		MaxOpenPerHost:      clone.PoolMaxOpenPerHost,
+		HealthCheckInterval: time.Duration(clone.PoolHealthCheckIntervalSeconds) * time.Second,
+		MinBackoff:          time.Duration(clone.PoolMinBackoffSeconds) * time.Second,
+		MaxBackoff:          time.Duration(clone.PoolMaxBackoffSeconds) * time.Second,
+		Weighted:            clone.PoolWeighted,
+	}
+	pool, err := NewPool(clone.URLs, poolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	c.pool = pool
+
+	healthCtx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	go c.runHealthChecks(healthCtx)
+
+	return c, nil
+}
+
+// runHealthChecks drives the pool's periodic health check until ctx is
+// canceled, e.g. by Close.
+func (c *Client) runHealthChecks(ctx context.Context) {
+	ticker := time.NewTicker(c.pool.config.HealthCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.pool.HealthCheck(ctx)
+		}
+	}
+}
+
+// Close stops the Client's background health check goroutine. It does not
+// close pooled connections; callers that want those torn down should let
+// the process exit or add that as future work.
+func (c *Client) Close() {
+	c.closeOnce.Do(func() {
+		if c.cancel != nil {
+			c.cancel()
+		}
+	})
+}
+
+// Option configures an optional behavior of Client.Authenticate.
+type Option func(*options)
+
+type options struct {
+	withGroups bool
+}
+
+func getOpts(opt ...Option) options {
+	var opts options
+	for _, o := range opt {
+		o(&opts)
+	}
+	return opts
+}
+
+// WithGroups causes Authenticate to resolve and return the caller's group
+// membership in AuthResult.Groups. It's left opt-in because group
+// resolution costs an extra search that not every caller needs.
+func WithGroups() Option {
+	return func(o *options) { o.withGroups = true }
+}
+
+// AuthResult is the successful result of a Client.Authenticate call.
+type AuthResult struct {
+	// UserDN is the distinguished name of the authenticated user.
+	UserDN string
+
+	// Groups are the user's resolved group names. Only populated when
+	// WithGroups() is passed to Authenticate.
+	Groups []string
+}
+
+// Authenticate resolves username to a user DN, binds as that user with
+// password, and, if WithGroups() is passed, resolves the user's group
+// membership.
+func (c *Client) Authenticate(ctx context.Context, username, password string, opt ...Option) (*AuthResult, error) {
+	const op = "ldap.(Client).Authenticate"
+	if username == "" {
+		return nil, fmt.Errorf("%s: missing username: %w", op, ErrInvalidParameter)
+	}
+	if password == "" && !c.config.AllowEmptyPasswordBinds {
+		return nil, fmt.Errorf("%s: missing password: %w", op, ErrInvalidParameter)
+	}
+	opts := getOpts(opt...)
+
+	conn, err := c.getConnection(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer conn.Close()
+
+	userDN, err := c.resolveUserDN(ctx, conn, username)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := conn.Bind(userDN, password); err != nil {
+		return nil, fmt.Errorf("%s: unable to bind as user: %w", op, err)
+	}
+
+	result := &AuthResult{UserDN: userDN}
+	if opts.withGroups {
+		groups, err := c.resolveGroups(ctx, conn, userDN, username)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		result.Groups = groups
+	}
+	return result, nil
+}
+
+// getConnection returns a connection from the pool, preferring an idle one
+// and failing over between URLs per the pool's health tracking. The
+// returned Conn's Close releases it back to the pool rather than tearing
+// down the underlying connection.
+func (c *Client) getConnection(ctx context.Context) (Conn, error) {
+	const op = "ldap.(Client).getConnection"
+	conn, err := c.pool.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return conn, nil
+}
+
+// dial opens a single connection to url, applying StartTLS if configured.
+func (c *Client) dial(ctx context.Context, url string) (Conn, error) {
+	const op = "ldap.(Client).dial"
+	tlsConfig, err := c.tlsConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn, err := ldap.DialURL(url, ldap.DialWithTLSConfig(tlsConfig))
+	if err != nil {
+		return nil, fmt.Errorf("%s: unable to dial %q: %w", op, url, err)
+	}
+	if c.config.StartTLS {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("%s: unable to start tls on %q: %w", op, url, err)
+		}
+	}
+	return conn, nil
+}
+
+// referralDialer returns ReferralDialer, or dial (the same dialer and TLS
+// configuration used for ClientConfig.URLs) if it's unset.
+func (c *Client) referralDialer() func(ctx context.Context, url string) (Conn, error) {
+	if c.config.ReferralDialer != nil {
+		return c.config.ReferralDialer
+	}
+	return c.dial
+}
+
+// tlsConfig builds the *tls.Config used to dial the directory, preferring
+// TLSConfigFunc when set over the static PEM fields.
+func (c *Client) tlsConfig(ctx context.Context) (*tls.Config, error) {
+	const op = "ldap.(Client).tlsConfig"
+	if c.config.TLSConfigFunc != nil {
+		tlsConfig, err := c.config.TLSConfigFunc(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		return tlsConfig, nil
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: c.config.InsecureTLS}
+	if c.config.Certificate != "" {
+		pool, err := certPool(c.config.Certificate)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if c.config.ClientTLSCert != "" && c.config.ClientTLSKey != "" {
+		cert, err := tls.X509KeyPair([]byte(c.config.ClientTLSCert), []byte(c.config.ClientTLSKey))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// resolveUserDN resolves username to the DN of the entry to bind as. In
+// ADMode, username is matched against either sAMAccountName or
+// userPrincipalName, and the entry's userAccountControl is checked so a
+// disabled, locked or password-expired account is rejected before a bind is
+// even attempted.
+func (c *Client) resolveUserDN(ctx context.Context, conn Conn, username string) (string, error) {
+	const op = "ldap.(Client).resolveUserDN"
+
+	var filter string
+	switch {
+	case c.config.ADMode:
+		filter = adLoginFilter(username, c.config.UPNDomain)
+	case c.config.UserFilter != "":
+		rendered, err := c.renderFilter(c.config.UserFilter, username, "")
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", op, err)
+		}
+		filter = rendered
+	case c.config.UPNDomain != "":
+		filter = fmt.Sprintf("(userPrincipalName=%s@%s)", ldap.EscapeFilter(username), ldap.EscapeFilter(c.config.UPNDomain))
+	default:
+		filter = fmt.Sprintf("(%s=%s)", c.config.userAttr(), ldap.EscapeFilter(username))
+	}
+
+	req := ldap.NewSearchRequest(
+		c.config.UserDN,
+		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+		filter,
+		[]string{"dn", userAccountControlAttr},
+		nil,
+	)
+	result, err := conn.Search(req)
+	if err != nil {
+		return "", fmt.Errorf("%s: unable to search for user: %w", op, err)
+	}
+	entries := result.Entries
+	if c.config.FollowReferrals && len(result.Referrals) > 0 {
+		referred, err := searchReferrals(ctx, result.Referrals, req, c.config.PageSize, c.config.BindDN, c.config.BindPassword, c.referralDialer())
+		if err != nil {
+			return "", fmt.Errorf("%s: unable to search for user: %w", op, err)
+		}
+		entries = append(entries, referred...)
+	}
+	if len(entries) != 1 {
+		return "", fmt.Errorf("%s: expected one user entry, found %d: %w", op, len(entries), ErrInvalidParameter)
+	}
+	entry := entries[0]
+
+	if c.config.ADMode {
+		if err := rejectDisabledADAccount(entry); err != nil {
+			return "", fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return entry.DN, nil
+}
+
+// rejectDisabledADAccount returns an error if entry's userAccountControl
+// marks the account as disabled, locked or having an expired password.
+func rejectDisabledADAccount(entry *ldap.Entry) error {
+	const op = "ldap.rejectDisabledADAccount"
+	raw := entry.GetAttributeValue(userAccountControlAttr)
+	if raw == "" {
+		return nil
+	}
+	flags, err := ParseUserAccountControl(raw)
+	if err != nil {
+		return err
+	}
+	switch {
+	case flags.Disabled:
+		return fmt.Errorf("%s: account %q is disabled: %w", op, entry.DN, ErrInvalidParameter)
+	case flags.Locked:
+		return fmt.Errorf("%s: account %q is locked: %w", op, entry.DN, ErrInvalidParameter)
+	case flags.PasswordExpired:
+		return fmt.Errorf("%s: account %q has an expired password: %w", op, entry.DN, ErrInvalidParameter)
+	}
+	return nil
+}
+
+// resolveGroups searches for userDN's group membership and returns the
+// resolved group names.
+func (c *Client) resolveGroups(ctx context.Context, conn Conn, userDN, username string) ([]string, error) {
+	const op = "ldap.(Client).resolveGroups"
+
+	var filter string
+	switch {
+	case c.config.ADMode && !c.config.UseTokenGroups:
+		filter = nestedGroupFilter(userDN)
+	default:
+		tmpl := c.config.GroupFilter
+		if tmpl == "" {
+			tmpl = DefaultGroupFilter
+		}
+		rendered, err := c.renderFilter(tmpl, username, userDN)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		filter = rendered
+	}
+
+	req := ldap.NewSearchRequest(
+		c.config.GroupDN,
+		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+		filter,
+		[]string{"dn", c.config.groupAttr(), "sAMAccountName"},
+		nil,
+	)
+	// pagedSearch applies the RFC 2696 paged-results control and chases
+	// referrals to other naming contexts, so a large forest doesn't
+	// silently truncate the group list at the server's sizeLimit.
+	entries, err := pagedSearch(ctx, conn, req, c.config.PageSize, c.config.BindDN, c.config.BindPassword, c.config.FollowReferrals, c.referralDialer())
+	if err != nil {
+		return nil, fmt.Errorf("%s: unable to search for groups: %w", op, err)
+	}
+
+	groups := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		name, err := groupName(entry, c.config.GroupNameMode, c.config.groupAttr(), c.config.CaseSensitiveNames)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		groups = append(groups, name)
+	}
+	return groups, nil
+}
+
+// filterContext is the template context available to GroupFilter and
+// UserFilter templates.
+type filterContext struct {
+	UserAttr string
+	Username string
+	UserDN   string
+}
+
+// renderFilter renders tmpl with username/userDN escaped via
+// ldap.EscapeFilter, the same as every other filter built in resolveUserDN.
+// username in particular is attacker-facing, pre-auth input; without
+// escaping, a login like `admin)(&(objectClass=*` would let an
+// unauthenticated caller rewrite the rest of the search filter.
+func (c *Client) renderFilter(tmpl, username, userDN string) (string, error) {
+	const op = "ldap.(Client).renderFilter"
+	t, err := template.New("filter").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("%s: invalid filter template: %w", op, err)
+	}
+	var buf bytes.Buffer
+	ctx := filterContext{
+		UserAttr: c.config.userAttr(),
+		Username: ldap.EscapeFilter(username),
+		UserDN:   ldap.EscapeFilter(userDN),
+	}
+	if err := t.Execute(&buf, ctx); err != nil {
+		return "", fmt.Errorf("%s: unable to render filter: %w", op, err)
+	}
+	return buf.String(), nil
+}
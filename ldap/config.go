@@ -1,6 +1,7 @@
 package ldap
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/pem"
@@ -29,14 +30,41 @@ const (
 
 	// DefaultTLSMaxVersion for the ClientConfig.TLSMaxVersion
 	DefaultTLSMaxVersion = "tls12"
+
+	// DefaultPageSize for the ClientConfig.PageSize
+	DefaultPageSize = 1000
 )
 
 type ClientConfig struct {
 	// URLs are the URLs to use when connecting to a directory (default:
-	// ldap://127.0.0.1).  When multiple URLs are specified; they are tried
-	// in the order specified.
+	// ldap://127.0.0.1).  When multiple URLs are specified, the Client's
+	// connection pool tries them in order (or, if PoolWeighted is set, in
+	// random order) and fails over between them.
 	URLs []string `json:"urls"`
 
+	// PoolMaxIdlePerHost is the maximum number of idle connections the
+	// Client's connection pool keeps open per URL (default: 2).
+	PoolMaxIdlePerHost int `json:"pool_max_idle_per_host"`
+
+	// PoolMaxOpenPerHost is the maximum number of connections, idle or in
+	// use, the Client's connection pool allows per URL (default: 10).
+	PoolMaxOpenPerHost int `json:"pool_max_open_per_host"`
+
+	// PoolHealthCheckIntervalSeconds is how often, in seconds, an idle URL
+	// is health-checked with a WhoAmI request (default: 30).
+	PoolHealthCheckIntervalSeconds int `json:"pool_health_check_interval_seconds"`
+
+	// PoolMinBackoffSeconds and PoolMaxBackoffSeconds, in seconds, bound
+	// the exponential backoff applied to a URL after a failed dial or
+	// health check (defaults: 1, 60).
+	PoolMinBackoffSeconds int `json:"pool_min_backoff_seconds"`
+	PoolMaxBackoffSeconds int `json:"pool_max_backoff_seconds"`
+
+	// PoolWeighted, if true, selects among healthy URLs at random
+	// (spreading load across replicas) instead of trying them in the
+	// order given in URLs.
+	PoolWeighted bool `json:"pool_weighted"`
+
 	// UserDN is the base distinguished name to use when searching for users
 	// (eg: ou=People,dc=example,dc=org)
 	UserDN string `json:"userdn"`
@@ -99,6 +127,15 @@ type ClientConfig struct {
 	// encoded x509 (optional)
 	ClientTLSKey string `json:"client_tls_key"`
 
+	// TLSConfigFunc, if set, is called to obtain the *tls.Config used for
+	// the connection instead of building one from Certificate,
+	// ClientTLSCert and ClientTLSKey. This is the escape hatch for
+	// operators who need the client certificate rotated automatically,
+	// e.g. from a SPIFFE Workload API socket via WithSPIFFESource, rather
+	// than read once from a static PEM on disk. It's not serializable and
+	// can only be set when constructing a ClientConfig in Go.
+	TLSConfigFunc func(context.Context) (*tls.Config, error) `json:"-"`
+
 	// InsecureTLS will skip the verification of the directory service's
 	// certificate when making a client connection (optional).
 	// Warning: this is insecure
@@ -137,6 +174,18 @@ type ClientConfig struct {
 	// security groups including nested ones.",
 	UseTokenGroups bool `json:"use_token_groups"`
 
+	// ADMode: if true, enables Active Directory specific behavior on top of
+	// the generic LDAP support: a login is resolved against both
+	// sAMAccountName and userPrincipalName (using UPNDomain as the domain),
+	// nested group membership is resolved via the
+	// LDAP_MATCHING_RULE_IN_CHAIN matching rule OID when UseTokenGroups is
+	// false (with UseTokenGroups true, the GroupFilter search is used and
+	// the tokenGroups attribute itself is not read), and the
+	// userAccountControl attribute is checked so Authenticate rejects
+	// disabled, locked or password-expired accounts before binding as the
+	// user.
+	ADMode bool `json:"ad_mode"`
+
 	// RequestTimeout in seconds, for the connection when making requests
 	// against the server before returning back an error.
 	RequestTimeout int `json:"request_timeout"`
@@ -144,7 +193,42 @@ type ClientConfig struct {
 	// DeprecatedVaultPre111GroupCNBehavior: if true, group searching reverts to
 	// the pre 1.1.1 Vault behavior.
 	// see: https://www.vaultproject.io/docs/upgrading/upgrade-to-1.1.1
+	//
+	// Deprecated: use GroupNameMode instead. GroupNameModeFromDeprecatedBehavior
+	// returns the GroupNameMode equivalent to this boolean.
 	DeprecatedVaultPre111GroupCNBehavior *bool `json:"use_pre111_group_cn_behavior"`
+
+	// GroupNameMode controls how a group identifier is extracted from a
+	// GroupFilter search result entry (default: GroupNameCN). It
+	// supersedes DeprecatedVaultPre111GroupCNBehavior, which only chose
+	// between two of these behaviors.
+	GroupNameMode GroupNameMode `json:"group_name_mode"`
+
+	// CaseSensitiveNames: if false (the default), group names returned
+	// according to GroupNameMode are lowercased so downstream policy
+	// mappers see a stable, canonical form. This only affects names
+	// returned to the caller; the login name is still sent to the server
+	// as-is at bind time.
+	CaseSensitiveNames bool `json:"case_sensitive_names"`
+
+	// PageSize is the number of entries requested per page when searching
+	// for groups (default: 1000). The search uses the RFC 2696
+	// paged-results control and transparently iterates pages until the
+	// server reports none remain, so group lists aren't silently
+	// truncated at the server's sizeLimit.
+	PageSize int `json:"page_size"`
+
+	// FollowReferrals: if true, and the server returns a referral to
+	// another naming context while searching for users or groups (common
+	// in multi-domain Active Directory forests), the client rebinds to
+	// the referred host using the same credentials and TLS configuration
+	// and continues the search there.
+	FollowReferrals bool `json:"follow_referrals"`
+
+	// ReferralDialer, if set, is used to dial a referred URL instead of
+	// the default dialer used for ClientConfig.URLs. It's only consulted
+	// when FollowReferrals is true.
+	ReferralDialer func(ctx context.Context, url string) (Conn, error) `json:"-"`
 }
 
 func (c *ClientConfig) clone() (*ClientConfig, error) {
@@ -152,6 +236,22 @@ func (c *ClientConfig) clone() (*ClientConfig, error) {
 	return &clone, nil
 }
 
+// userAttr returns UserAttr, or DefaultUserAttr if it's unset.
+func (c *ClientConfig) userAttr() string {
+	if c.UserAttr == "" {
+		return DefaultUserAttr
+	}
+	return c.UserAttr
+}
+
+// groupAttr returns GroupAttr, or DefaultGroupAttr if it's unset.
+func (c *ClientConfig) groupAttr() string {
+	if c.GroupAttr == "" {
+		return DefaultGroupAttr
+	}
+	return c.GroupAttr
+}
+
 func (c *ClientConfig) validate() error {
 	const op = "ldap.(ClientConfig).validate"
 	if len(c.URLs) == 0 {
@@ -177,6 +277,9 @@ func (c *ClientConfig) validate() error {
 		(c.ClientTLSCert == "" && c.ClientTLSKey != "") {
 		return fmt.Errorf("%s: both client_tls_cert and client_tls_key must be set in configuration: %w", op, ErrInvalidParameter)
 	}
+	if c.TLSConfigFunc != nil && (c.Certificate != "" || c.ClientTLSCert != "" || c.ClientTLSKey != "") {
+		return fmt.Errorf("%s: tls_config_func cannot be combined with the static certificate/client_tls_cert/client_tls_key PEM fields: %w", op, ErrInvalidParameter)
+	}
 	if c.ClientTLSCert != "" && c.ClientTLSKey != "" {
 		if _, err := tls.X509KeyPair([]byte(c.ClientTLSCert), []byte(c.ClientTLSKey)); err != nil {
 			return fmt.Errorf("%s: failed to parse client X509 key pair: %w", op, err)
@@ -185,6 +288,17 @@ func (c *ClientConfig) validate() error {
 	return nil
 }
 
+// certPool parses pemBlock and returns an x509.CertPool containing it, for
+// use as a connection's RootCAs.
+func certPool(pemBlock string) (*x509.CertPool, error) {
+	const op = "ldap.certPool"
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM([]byte(pemBlock)) {
+		return nil, fmt.Errorf("%s: unable to parse certificate pem: %w", op, ErrInvalidParameter)
+	}
+	return pool, nil
+}
+
 func validateCertificate(pemBlock []byte) error {
 	const op = "ldap.validateCertificate"
 	if pemBlock == nil {
@@ -0,0 +1,68 @@
+package ldap
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"testing"
+)
+
+func validConfig() *ClientConfig {
+	return &ClientConfig{
+		URLs:          []string{DefaultURL},
+		TLSMinVersion: DefaultTLSMinVersion,
+		TLSMaxVersion: DefaultTLSMaxVersion,
+	}
+}
+
+func noopTLSConfigFunc(context.Context) (*tls.Config, error) { return &tls.Config{}, nil }
+
+func TestClientConfig_Validate_TLSConfigFuncExcludesCertificateFields(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  func() *ClientConfig
+	}{
+		{
+			name: "server certificate",
+			cfg: func() *ClientConfig {
+				c := validConfig()
+				c.TLSConfigFunc = noopTLSConfigFunc
+				c.Certificate = "pem"
+				return c
+			},
+		},
+		{
+			name: "client cert",
+			cfg: func() *ClientConfig {
+				c := validConfig()
+				c.TLSConfigFunc = noopTLSConfigFunc
+				c.ClientTLSCert = "pem"
+				return c
+			},
+		},
+		{
+			name: "client key",
+			cfg: func() *ClientConfig {
+				c := validConfig()
+				c.TLSConfigFunc = noopTLSConfigFunc
+				c.ClientTLSKey = "pem"
+				return c
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg().validate(); !errors.Is(err, ErrInvalidParameter) {
+				t.Fatalf("expected ErrInvalidParameter when tls_config_func is combined with a static cert field, got %v", err)
+			}
+		})
+	}
+}
+
+func TestClientConfig_Validate_TLSConfigFuncAloneIsValid(t *testing.T) {
+	c := validConfig()
+	c.TLSConfigFunc = noopTLSConfigFunc
+	if err := c.validate(); err != nil {
+		t.Fatalf("expected tls_config_func alone to be valid, got %v", err)
+	}
+}
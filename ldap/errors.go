@@ -0,0 +1,7 @@
+package ldap
+
+import "errors"
+
+// ErrInvalidParameter is returned (wrapped with additional context via
+// fmt.Errorf's %w) when a caller-supplied parameter fails validation.
+var ErrInvalidParameter = errors.New("invalid parameter")
@@ -0,0 +1,100 @@
+package ldap
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// GroupNameMode selects how a group identifier is extracted from an entry
+// returned by a GroupFilter search.
+type GroupNameMode int
+
+const (
+	// GroupNameCN parses the first CN RDN out of the entry's DN. This is
+	// the default and matches the historical (post 1.1.1) Vault behavior.
+	GroupNameCN GroupNameMode = iota
+
+	// GroupNameDN returns the entry's full DN, unparsed.
+	GroupNameDN
+
+	// GroupNameAttr returns the value of the entry's configured GroupAttr
+	// attribute, verbatim.
+	GroupNameAttr
+
+	// GroupNameSAM returns the entry's sAMAccountName attribute, for
+	// Active Directory directories where that's the canonical group
+	// identifier.
+	GroupNameSAM
+)
+
+// String implements fmt.Stringer.
+func (m GroupNameMode) String() string {
+	switch m {
+	case GroupNameCN:
+		return "cn"
+	case GroupNameDN:
+		return "dn"
+	case GroupNameAttr:
+		return "attr"
+	case GroupNameSAM:
+		return "sam"
+	default:
+		return fmt.Sprintf("GroupNameMode(%d)", int(m))
+	}
+}
+
+// GroupNameModeFromDeprecatedBehavior returns the GroupNameMode equivalent
+// of the now-deprecated DeprecatedVaultPre111GroupCNBehavior boolean, for
+// callers migrating existing configuration. Upstream's pre-1.1.1 switch
+// (getCN) only ever changed how strictly the "CN" RDN attribute type was
+// matched -- strict-case when nil or true, case-insensitive (EqualFold)
+// when false -- both branches extracted the CN value and neither ever
+// returned the bare DN, so every setting of the deprecated flag is
+// equivalent to GroupNameCN.
+func GroupNameModeFromDeprecatedBehavior(preVault111 *bool) GroupNameMode {
+	return GroupNameCN
+}
+
+// groupName extracts the group identifier from entry according to mode,
+// using groupAttr as the attribute consulted by GroupNameAttr, and
+// lowercases the result unless caseSensitive is true.
+func groupName(entry *ldap.Entry, mode GroupNameMode, groupAttr string, caseSensitive bool) (string, error) {
+	const op = "ldap.groupName"
+
+	var name string
+	switch mode {
+	case GroupNameDN:
+		name = entry.DN
+	case GroupNameAttr:
+		name = entry.GetAttributeValue(groupAttr)
+	case GroupNameSAM:
+		name = entry.GetAttributeValue("sAMAccountName")
+	case GroupNameCN:
+		parsed, err := ldap.ParseDN(entry.DN)
+		if err != nil {
+			return "", fmt.Errorf("%s: unable to parse group dn %q: %w", op, entry.DN, err)
+		}
+		for _, rdn := range parsed.RDNs {
+			for _, attr := range rdn.Attributes {
+				if strings.EqualFold(attr.Type, "cn") {
+					name = attr.Value
+				}
+			}
+			if name != "" {
+				break
+			}
+		}
+		if name == "" {
+			return "", fmt.Errorf("%s: no CN found in group dn %q: %w", op, entry.DN, ErrInvalidParameter)
+		}
+	default:
+		return "", fmt.Errorf("%s: unsupported group name mode %s: %w", op, mode, ErrInvalidParameter)
+	}
+
+	if !caseSensitive {
+		name = strings.ToLower(name)
+	}
+	return name, nil
+}
@@ -0,0 +1,75 @@
+package ldap
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+func newGroupEntry(dn string, attrs map[string][]string) *ldap.Entry {
+	var ldapAttrs []*ldap.EntryAttribute
+	for name, values := range attrs {
+		ldapAttrs = append(ldapAttrs, &ldap.EntryAttribute{Name: name, Values: values})
+	}
+	return &ldap.Entry{DN: dn, Attributes: ldapAttrs}
+}
+
+func TestGroupName(t *testing.T) {
+	entry := newGroupEntry(
+		"CN=Admins,OU=Groups,DC=example,DC=com",
+		map[string][]string{
+			"cn":             {"Admins"},
+			"sAMAccountName": {"Admins"},
+		},
+	)
+
+	tests := []struct {
+		name          string
+		mode          GroupNameMode
+		caseSensitive bool
+		want          string
+	}{
+		{name: "cn mode lowercased", mode: GroupNameCN, caseSensitive: false, want: "admins"},
+		{name: "cn mode case sensitive", mode: GroupNameCN, caseSensitive: true, want: "Admins"},
+		{name: "dn mode lowercased", mode: GroupNameDN, caseSensitive: false, want: "cn=admins,ou=groups,dc=example,dc=com"},
+		{name: "attr mode", mode: GroupNameAttr, caseSensitive: true, want: "Admins"},
+		{name: "sam mode", mode: GroupNameSAM, caseSensitive: true, want: "Admins"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := groupName(entry, tt.mode, "cn", tt.caseSensitive)
+			if err != nil {
+				t.Fatalf("groupName: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGroupName_CNModeRequiresCNInDN(t *testing.T) {
+	entry := newGroupEntry("OU=Groups,DC=example,DC=com", nil)
+	if _, err := groupName(entry, GroupNameCN, "cn", false); !errors.Is(err, ErrInvalidParameter) {
+		t.Fatalf("expected ErrInvalidParameter for a DN with no CN, got %v", err)
+	}
+}
+
+func TestGroupName_UnsupportedMode(t *testing.T) {
+	entry := newGroupEntry("CN=Admins,DC=example,DC=com", nil)
+	if _, err := groupName(entry, GroupNameMode(99), "cn", false); !errors.Is(err, ErrInvalidParameter) {
+		t.Fatalf("expected ErrInvalidParameter for an unsupported mode, got %v", err)
+	}
+}
+
+func TestGroupNameModeFromDeprecatedBehavior(t *testing.T) {
+	yes := true
+	no := false
+
+	for _, preVault111 := range []*bool{nil, &yes, &no} {
+		if got := GroupNameModeFromDeprecatedBehavior(preVault111); got != GroupNameCN {
+			t.Fatalf("expected GroupNameCN regardless of the deprecated flag, got %v (flag=%v)", got, preVault111)
+		}
+	}
+}
@@ -0,0 +1,289 @@
+package ldap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// errHostBusy is returned by getFromHost when a host has hit
+// PoolConfig.MaxOpenPerHost. It's not a health problem, so Get must not
+// treat it the same as a dial or health-check failure.
+var errHostBusy = errors.New("max open connections reached for host")
+
+// Conn is the subset of *ldap.Conn used by Pool. It's satisfied by
+// *ldap.Conn and exists so callers can fake a connection in tests.
+type Conn interface {
+	Bind(username, password string) error
+	Search(req *ldap.SearchRequest) (*ldap.SearchResult, error)
+	Modify(req *ldap.ModifyRequest) error
+	WhoAmI(controls []ldap.Control) (*ldap.WhoAmIResult, error)
+	Close() error
+}
+
+// PoolConfig controls the sizing and health-checking behavior of a Pool.
+type PoolConfig struct {
+	// MaxIdlePerHost is the maximum number of idle connections kept open
+	// per URL (default: 2).
+	MaxIdlePerHost int
+
+	// MaxOpenPerHost is the maximum number of connections, idle or in use,
+	// allowed per URL (default: 10).
+	MaxOpenPerHost int
+
+	// HealthCheckInterval is how often an idle URL is health-checked with
+	// a WhoAmI request (default: 30s).
+	HealthCheckInterval time.Duration
+
+	// MinBackoff and MaxBackoff bound the exponential backoff applied to a
+	// URL after a failed dial or health check (defaults: 1s, 1m).
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// Weighted, if true, selects among healthy URLs at random (spreading
+	// load across replicas) instead of trying them in configured order.
+	Weighted bool
+
+	// Dial opens a new connection to url. It's required: NewPool returns an
+	// error if it's nil. Client sets it to dial with the ClientConfig's TLS
+	// settings.
+	Dial func(ctx context.Context, url string) (Conn, error)
+}
+
+func (c *PoolConfig) withDefaults() *PoolConfig {
+	cfg := *c
+	if cfg.MaxIdlePerHost <= 0 {
+		cfg.MaxIdlePerHost = 2
+	}
+	if cfg.MaxOpenPerHost <= 0 {
+		cfg.MaxOpenPerHost = 10
+	}
+	if cfg.HealthCheckInterval <= 0 {
+		cfg.HealthCheckInterval = 30 * time.Second
+	}
+	if cfg.MinBackoff <= 0 {
+		cfg.MinBackoff = time.Second
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = time.Minute
+	}
+	return &cfg
+}
+
+// hostState tracks the health and idle connections of a single URL.
+type hostState struct {
+	url string
+
+	mu          sync.Mutex
+	idle        []Conn
+	open        int
+	backoff     time.Duration
+	nextAttempt time.Time
+	lastChecked time.Time
+}
+
+func (h *hostState) healthy(now time.Time) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return now.After(h.nextAttempt)
+}
+
+// Pool maintains persistent LDAP connections per URL from a ClientConfig,
+// trying URLs in configured order (or, with PoolConfig.Weighted, in random
+// order) and failing over between them. A URL that fails a dial or health
+// check is backed off exponentially and automatically re-promoted once
+// its backoff elapses.
+type Pool struct {
+	config *PoolConfig
+	hosts  []*hostState
+}
+
+// NewPool creates a Pool that dials urls with config.Dial.
+func NewPool(urls []string, config *PoolConfig) (*Pool, error) {
+	const op = "ldap.NewPool"
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("%s: at least one url must be provided: %w", op, ErrInvalidParameter)
+	}
+	if config == nil {
+		config = &PoolConfig{}
+	}
+	if config.Dial == nil {
+		return nil, fmt.Errorf("%s: missing Dial func: %w", op, ErrInvalidParameter)
+	}
+	cfg := config.withDefaults()
+
+	hosts := make([]*hostState, 0, len(urls))
+	for _, u := range urls {
+		hosts = append(hosts, &hostState{url: u})
+	}
+	return &Pool{config: cfg, hosts: hosts}, nil
+}
+
+// Get returns a healthy connection, preferring an idle one from the pool and
+// dialing a new one otherwise. It tries hosts in order (or, if Weighted is
+// set, in random order), skipping hosts that are currently backed off.
+func (p *Pool) Get(ctx context.Context) (Conn, error) {
+	const op = "ldap.(Pool).Get"
+	now := time.Now()
+
+	order := make([]int, len(p.hosts))
+	for i := range order {
+		order[i] = i
+	}
+	if p.config.Weighted {
+		rand.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+	}
+
+	var lastErr error
+	for _, i := range order {
+		h := p.hosts[i]
+		if !h.healthy(now) {
+			continue
+		}
+		conn, err := p.getFromHost(ctx, h)
+		if err != nil {
+			lastErr = err
+			// A host that's merely at its concurrency limit is healthy,
+			// just busy - back off dial/health failures only, or a brief
+			// traffic spike would demote the host and cascade the load
+			// onto its neighbors.
+			if !errors.Is(err, errHostBusy) {
+				h.recordFailure(p.config)
+			}
+			continue
+		}
+		h.recordSuccess()
+		return &pooledConn{Conn: conn, host: h, pool: p}, nil
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no healthy urls available")
+	}
+	return nil, fmt.Errorf("%s: %w: %w", op, lastErr, ErrInvalidParameter)
+}
+
+func (p *Pool) getFromHost(ctx context.Context, h *hostState) (Conn, error) {
+	h.mu.Lock()
+	if n := len(h.idle); n > 0 {
+		conn := h.idle[n-1]
+		h.idle = h.idle[:n-1]
+		h.mu.Unlock()
+		return conn, nil
+	}
+	if h.open >= p.config.MaxOpenPerHost {
+		h.mu.Unlock()
+		return nil, fmt.Errorf("%s: %w", h.url, errHostBusy)
+	}
+	h.open++
+	h.mu.Unlock()
+
+	conn, err := p.config.Dial(ctx, h.url)
+	if err != nil {
+		h.mu.Lock()
+		h.open--
+		h.mu.Unlock()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// Put returns conn to the idle pool for its host, closing it instead if the
+// host is already at MaxIdlePerHost.
+func (p *Pool) Put(url string, conn Conn) {
+	for _, h := range p.hosts {
+		if h.url != url {
+			continue
+		}
+		p.release(h, conn)
+		return
+	}
+	_ = conn.Close()
+}
+
+func (p *Pool) release(h *hostState, conn Conn) {
+	h.mu.Lock()
+	if len(h.idle) >= p.config.MaxIdlePerHost {
+		h.open--
+		h.mu.Unlock()
+		_ = conn.Close()
+		return
+	}
+	h.idle = append(h.idle, conn)
+	h.mu.Unlock()
+}
+
+// pooledConn wraps a Conn obtained from Pool.Get so that callers can keep
+// using the ordinary defer conn.Close() pattern: Close returns the
+// connection to its host's idle pool (or closes it outright if the host is
+// already at MaxIdlePerHost) instead of tearing down the underlying
+// connection.
+type pooledConn struct {
+	Conn
+	host *hostState
+	pool *Pool
+}
+
+// Close implements Conn by releasing the connection back to the pool.
+func (pc *pooledConn) Close() error {
+	pc.pool.release(pc.host, pc.Conn)
+	return nil
+}
+
+func (h *hostState) recordFailure(cfg *PoolConfig) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.backoff == 0 {
+		h.backoff = cfg.MinBackoff
+	} else {
+		h.backoff *= 2
+		if h.backoff > cfg.MaxBackoff {
+			h.backoff = cfg.MaxBackoff
+		}
+	}
+	h.nextAttempt = time.Now().Add(h.backoff)
+}
+
+func (h *hostState) recordSuccess() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.backoff = 0
+	h.nextAttempt = time.Time{}
+}
+
+// HealthCheck runs a WhoAmI request against an idle connection for each
+// host, demoting any host that fails it. Callers typically run this on a
+// ticker at PoolConfig.HealthCheckInterval.
+func (p *Pool) HealthCheck(ctx context.Context) {
+	now := time.Now()
+	for _, h := range p.hosts {
+		h.mu.Lock()
+		due := now.Sub(h.lastChecked) >= p.config.HealthCheckInterval
+		h.lastChecked = now
+		h.mu.Unlock()
+		if !due {
+			continue
+		}
+		conn, err := p.getFromHost(ctx, h)
+		if err != nil {
+			// A busy host is not unhealthy; see errHostBusy.
+			if !errors.Is(err, errHostBusy) {
+				h.recordFailure(p.config)
+			}
+			continue
+		}
+		if _, err := conn.WhoAmI(nil); err != nil {
+			h.recordFailure(p.config)
+			h.mu.Lock()
+			h.open--
+			h.mu.Unlock()
+			_ = conn.Close()
+			continue
+		}
+		h.recordSuccess()
+		p.Put(h.url, conn)
+	}
+}
@@ -0,0 +1,132 @@
+package ldap
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+type fakeConn struct {
+	closed bool
+}
+
+func (f *fakeConn) Bind(username, password string) error                       { return nil }
+func (f *fakeConn) Search(req *ldap.SearchRequest) (*ldap.SearchResult, error) { return nil, nil }
+func (f *fakeConn) Modify(req *ldap.ModifyRequest) error                       { return nil }
+func (f *fakeConn) WhoAmI(controls []ldap.Control) (*ldap.WhoAmIResult, error) { return nil, nil }
+func (f *fakeConn) Close() error                                               { f.closed = true; return nil }
+
+func newTestPool(t *testing.T, cfg *PoolConfig) *Pool {
+	t.Helper()
+	pool, err := NewPool([]string{"ldap://host-a", "ldap://host-b"}, cfg)
+	if err != nil {
+		t.Fatalf("NewPool: %v", err)
+	}
+	return pool
+}
+
+func TestPool_MaxOpenPerHost_DoesNotBackoffBusyHost(t *testing.T) {
+	dialed := 0
+	pool := newTestPool(t, &PoolConfig{
+		MaxOpenPerHost: 1,
+		Dial: func(ctx context.Context, url string) (Conn, error) {
+			dialed++
+			return &fakeConn{}, nil
+		},
+	})
+
+	// First Get exhausts host-a's single connection slot (never Put back).
+	conn1, err := pool.Get(context.Background())
+	if err != nil {
+		t.Fatalf("first Get: %v", err)
+	}
+	if _, ok := conn1.(*pooledConn); !ok {
+		t.Fatalf("expected *pooledConn, got %T", conn1)
+	}
+
+	// host-a is now busy, but still healthy: a second Get should fail over
+	// to host-b rather than erroring, and must not have backed off host-a.
+	conn2, err := pool.Get(context.Background())
+	if err != nil {
+		t.Fatalf("second Get: %v", err)
+	}
+	conn2.Close()
+
+	hostA := pool.hosts[0]
+	if hostA.backoff != 0 {
+		t.Fatalf("busy host should not be backed off, got backoff=%v", hostA.backoff)
+	}
+	if !hostA.healthy(time.Now()) {
+		t.Fatalf("busy host should still be considered healthy")
+	}
+}
+
+func TestPool_GetFromHost_BusyReturnsErrHostBusy(t *testing.T) {
+	pool := newTestPool(t, &PoolConfig{
+		MaxOpenPerHost: 1,
+		Dial: func(ctx context.Context, url string) (Conn, error) {
+			return &fakeConn{}, nil
+		},
+	})
+
+	h := pool.hosts[0]
+	if _, err := pool.getFromHost(context.Background(), h); err != nil {
+		t.Fatalf("first getFromHost: %v", err)
+	}
+	_, err := pool.getFromHost(context.Background(), h)
+	if !errors.Is(err, errHostBusy) {
+		t.Fatalf("expected errHostBusy, got %v", err)
+	}
+}
+
+func TestHostState_RecordFailure_ExponentialBackoff(t *testing.T) {
+	cfg := (&PoolConfig{MinBackoff: time.Second, MaxBackoff: 4 * time.Second}).withDefaults()
+	h := &hostState{url: "ldap://host-a"}
+
+	h.recordFailure(cfg)
+	if h.backoff != time.Second {
+		t.Fatalf("expected initial backoff of 1s, got %v", h.backoff)
+	}
+	h.recordFailure(cfg)
+	if h.backoff != 2*time.Second {
+		t.Fatalf("expected backoff to double to 2s, got %v", h.backoff)
+	}
+	h.recordFailure(cfg)
+	if h.backoff != cfg.MaxBackoff {
+		t.Fatalf("expected backoff capped at %v, got %v", cfg.MaxBackoff, h.backoff)
+	}
+	if h.healthy(time.Now()) {
+		t.Fatalf("host should not be healthy while backed off")
+	}
+
+	h.recordSuccess()
+	if h.backoff != 0 || !h.healthy(time.Now()) {
+		t.Fatalf("recordSuccess should reset backoff and restore health")
+	}
+}
+
+func TestPool_PutReusesIdleConnection(t *testing.T) {
+	dialed := 0
+	pool := newTestPool(t, &PoolConfig{
+		Dial: func(ctx context.Context, url string) (Conn, error) {
+			dialed++
+			return &fakeConn{}, nil
+		},
+	})
+
+	conn, err := pool.Get(context.Background())
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	conn.Close() // releases back to the idle pool via pooledConn.Close
+
+	if _, err := pool.Get(context.Background()); err != nil {
+		t.Fatalf("second Get: %v", err)
+	}
+	if dialed != 1 {
+		t.Fatalf("expected the idle connection to be reused, dialed %d times", dialed)
+	}
+}
@@ -0,0 +1,110 @@
+package ldap
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// pagedSearch runs req against conn using the RFC 2696 paged-results
+// control, fetching pageSize entries at a time and iterating until the
+// server reports no more pages, so a group search isn't silently truncated
+// at the server's sizeLimit. If the server returns a referral and
+// followReferrals is set, the referred host is searched with referralDial
+// using the same bind credentials, and its entries are merged in.
+func pagedSearch(
+	ctx context.Context,
+	conn Conn,
+	req *ldap.SearchRequest,
+	pageSize int,
+	bindDN, bindPassword string,
+	followReferrals bool,
+	referralDial func(ctx context.Context, url string) (Conn, error),
+) ([]*ldap.Entry, error) {
+	const op = "ldap.pagedSearch"
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
+	var entries []*ldap.Entry
+	paging := ldap.NewControlPaging(uint32(pageSize))
+	for {
+		req.Controls = append(withoutPagingControl(req.Controls), paging)
+
+		result, err := conn.Search(req)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		entries = append(entries, result.Entries...)
+
+		if followReferrals && len(result.Referrals) > 0 {
+			referred, err := searchReferrals(ctx, result.Referrals, req, pageSize, bindDN, bindPassword, referralDial)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", op, err)
+			}
+			entries = append(entries, referred...)
+		}
+
+		next := ldap.FindControl(result.Controls, ldap.ControlTypePaging)
+		if next == nil {
+			break
+		}
+		pagingControl, ok := next.(*ldap.ControlPaging)
+		if !ok || len(pagingControl.Cookie) == 0 {
+			break
+		}
+		paging.SetCookie(pagingControl.Cookie)
+	}
+	return entries, nil
+}
+
+// withoutPagingControl strips any existing paging control from controls so
+// pagedSearch can append its own without duplicating it across pages.
+func withoutPagingControl(controls []ldap.Control) []ldap.Control {
+	out := make([]ldap.Control, 0, len(controls))
+	for _, c := range controls {
+		if c.GetControlType() != ldap.ControlTypePaging {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// searchReferrals rebinds to each referred URL with bindDN/bindPassword and
+// re-runs req there, returning the combined entries. Each referred host is
+// itself paged with pageSize, since a referred naming context can just as
+// easily exceed one page as the original search did; referrals returned by
+// a referred host are not chased further, to bound the recursion.
+func searchReferrals(
+	ctx context.Context,
+	referrals []string,
+	req *ldap.SearchRequest,
+	pageSize int,
+	bindDN, bindPassword string,
+	referralDial func(ctx context.Context, url string) (Conn, error),
+) ([]*ldap.Entry, error) {
+	const op = "ldap.searchReferrals"
+	if referralDial == nil {
+		return nil, fmt.Errorf("%s: FollowReferrals is set but no ReferralDialer was configured: %w", op, ErrInvalidParameter)
+	}
+
+	var entries []*ldap.Entry
+	for _, url := range referrals {
+		conn, err := referralDial(ctx, url)
+		if err != nil {
+			return nil, fmt.Errorf("%s: unable to dial referral %q: %w", op, url, err)
+		}
+		if err := conn.Bind(bindDN, bindPassword); err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("%s: unable to bind to referral %q: %w", op, url, err)
+		}
+		referred, err := pagedSearch(ctx, conn, req, pageSize, bindDN, bindPassword, false, nil)
+		_ = conn.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: unable to search referral %q: %w", op, url, err)
+		}
+		entries = append(entries, referred...)
+	}
+	return entries, nil
+}
@@ -0,0 +1,123 @@
+package ldap
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// scriptedConn returns its pages in order from Search, for exercising
+// pagedSearch's pagination/referral loop without a real directory.
+type scriptedConn struct {
+	pages  []*ldap.SearchResult
+	call   int
+	closed bool
+	binds  []string
+}
+
+func (c *scriptedConn) Bind(username, password string) error {
+	c.binds = append(c.binds, username)
+	return nil
+}
+
+func (c *scriptedConn) Search(req *ldap.SearchRequest) (*ldap.SearchResult, error) {
+	if c.call >= len(c.pages) {
+		return nil, errors.New("scriptedConn: no more pages scripted")
+	}
+	result := c.pages[c.call]
+	c.call++
+	return result, nil
+}
+
+func (c *scriptedConn) Modify(req *ldap.ModifyRequest) error { return nil }
+
+func (c *scriptedConn) WhoAmI(controls []ldap.Control) (*ldap.WhoAmIResult, error) {
+	return nil, nil
+}
+
+func (c *scriptedConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+func entryNamed(dn string) *ldap.Entry { return &ldap.Entry{DN: dn} }
+
+func pagingControl(cookie string) *ldap.ControlPaging {
+	p := ldap.NewControlPaging(100)
+	if cookie != "" {
+		p.SetCookie([]byte(cookie))
+	} else {
+		p.SetCookie(nil)
+	}
+	return p
+}
+
+func newSearchRequest() *ldap.SearchRequest {
+	return ldap.NewSearchRequest(
+		"dc=example,dc=com", ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+		"(objectClass=*)", nil, nil,
+	)
+}
+
+func TestPagedSearch_IteratesUntilCookieExhausted(t *testing.T) {
+	conn := &scriptedConn{pages: []*ldap.SearchResult{
+		{Entries: []*ldap.Entry{entryNamed("cn=a")}, Controls: []ldap.Control{pagingControl("page2")}},
+		{Entries: []*ldap.Entry{entryNamed("cn=b")}, Controls: []ldap.Control{pagingControl("")}},
+	}}
+
+	entries, err := pagedSearch(context.Background(), conn, newSearchRequest(), 100, "", "", false, nil)
+	if err != nil {
+		t.Fatalf("pagedSearch: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries across both pages, got %d", len(entries))
+	}
+	if conn.call != 2 {
+		t.Fatalf("expected exactly 2 Search calls, got %d", conn.call)
+	}
+}
+
+func TestPagedSearch_FollowsReferrals(t *testing.T) {
+	referredConn := &scriptedConn{pages: []*ldap.SearchResult{
+		{Entries: []*ldap.Entry{entryNamed("cn=referred")}, Controls: []ldap.Control{pagingControl("")}},
+	}}
+	conn := &scriptedConn{pages: []*ldap.SearchResult{
+		{
+			Entries:   []*ldap.Entry{entryNamed("cn=local")},
+			Referrals: []string{"ldap://other-domain"},
+			Controls:  []ldap.Control{pagingControl("")},
+		},
+	}}
+
+	var dialed string
+	referralDial := func(ctx context.Context, url string) (Conn, error) {
+		dialed = url
+		return referredConn, nil
+	}
+
+	entries, err := pagedSearch(context.Background(), conn, newSearchRequest(), 100, "cn=svc", "pw", true, referralDial)
+	if err != nil {
+		t.Fatalf("pagedSearch: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected local + referred entries, got %d", len(entries))
+	}
+	if dialed != "ldap://other-domain" {
+		t.Fatalf("expected referral dialer to be called with the referred url, got %q", dialed)
+	}
+	if len(referredConn.binds) != 1 || referredConn.binds[0] != "cn=svc" {
+		t.Fatalf("expected referred connection to bind with the original bind DN, got %+v", referredConn.binds)
+	}
+	if !referredConn.closed {
+		t.Fatalf("expected the referred connection to be closed after use")
+	}
+}
+
+func TestSearchReferrals_RequiresDialer(t *testing.T) {
+	_, err := searchReferrals(context.Background(), []string{"ldap://other"}, newSearchRequest(), 100, "", "", nil)
+	if !errors.Is(err, ErrInvalidParameter) {
+		t.Fatalf("expected ErrInvalidParameter when no dialer is configured, got %v", err)
+	}
+}
@@ -0,0 +1,36 @@
+package ldap
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+
+	"github.com/spiffe/go-spiffe/v2/spiffetls/tlsconfig"
+	"github.com/spiffe/go-spiffe/v2/workloadapi"
+)
+
+// WithSPIFFESource returns a ClientConfig.TLSConfigFunc that sources the
+// client's x509 identity, and trusted CA bundle, from source rather than
+// from a static PEM on disk. Plugging this into TLSConfigFunc makes
+// certificate rotation a non-event: source refreshes its SVID from the
+// SPIFFE Workload API in the background and every new connection picks up
+// the latest material automatically.
+//
+// authorizer decides whether the directory's presented SVID is accepted,
+// e.g. tlsconfig.AuthorizeID(expectedServerID) or
+// tlsconfig.AuthorizeMemberOf(trustDomain). It's required: there is no
+// default, since accepting any peer SVID would authenticate the client to
+// the server without ever verifying the server is who it claims to be,
+// defeating the point of mTLS to the directory.
+func WithSPIFFESource(source *workloadapi.X509Source, authorizer tlsconfig.Authorizer) func(context.Context) (*tls.Config, error) {
+	return func(ctx context.Context) (*tls.Config, error) {
+		const op = "ldap.WithSPIFFESource"
+		if source == nil {
+			return nil, fmt.Errorf("%s: missing x509 source: %w", op, ErrInvalidParameter)
+		}
+		if authorizer == nil {
+			return nil, fmt.Errorf("%s: missing authorizer: %w", op, ErrInvalidParameter)
+		}
+		return tlsconfig.MTLSClientConfig(source, source, authorizer), nil
+	}
+}
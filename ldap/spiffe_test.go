@@ -0,0 +1,21 @@
+package ldap
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/spiffe/go-spiffe/v2/spiffetls/tlsconfig"
+	"github.com/spiffe/go-spiffe/v2/workloadapi"
+)
+
+func TestWithSPIFFESource_RequiresSourceAndAuthorizer(t *testing.T) {
+	if _, err := WithSPIFFESource(nil, tlsconfig.AuthorizeAny())(context.Background()); !errors.Is(err, ErrInvalidParameter) {
+		t.Fatalf("expected ErrInvalidParameter for a missing source, got %v", err)
+	}
+
+	source := &workloadapi.X509Source{}
+	if _, err := WithSPIFFESource(source, nil)(context.Background()); !errors.Is(err, ErrInvalidParameter) {
+		t.Fatalf("expected ErrInvalidParameter for a missing authorizer, got %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package ldap
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestHMACSigner_SignVerify(t *testing.T) {
+	signer := &HMACSigner{Key: []byte("a shared secret")}
+	payload := []byte("the payload")
+
+	sig, err := signer.Sign(payload)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	if err := signer.Verify(payload, sig); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+
+	other := &HMACSigner{Key: []byte("a different secret")}
+	if err := other.Verify(payload, sig); !errors.Is(err, ErrInvalidParameter) {
+		t.Fatalf("expected signature mismatch with a different key, got %v", err)
+	}
+}
+
+func TestEd25519Signer_SignVerify(t *testing.T) {
+	signer, err := GenerateEd25519Signer()
+	if err != nil {
+		t.Fatalf("GenerateEd25519Signer: %v", err)
+	}
+	payload := []byte("the payload")
+
+	sig, err := signer.Sign(payload)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	if err := signer.Verify(payload, sig); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+
+	otherPub, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	other := &Ed25519Signer{PublicKey: otherPub}
+	if err := other.Verify(payload, sig); !errors.Is(err, ErrInvalidParameter) {
+		t.Fatalf("expected signature mismatch with a different key, got %v", err)
+	}
+}
+
+func TestSTS_EncodeVerifyCredentialsRoundTrip(t *testing.T) {
+	s := &STS{signer: &HMACSigner{Key: []byte("key")}}
+	now := time.Now()
+	claims := &Claims{
+		UserDN:    "cn=alice,dc=example,dc=com",
+		Groups:    []string{"admins", "developers"},
+		IssuedAt:  now,
+		ExpiresAt: now.Add(time.Hour),
+	}
+
+	token, err := s.encode(claims)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	got, err := s.VerifyCredentials(token)
+	if err != nil {
+		t.Fatalf("VerifyCredentials: %v", err)
+	}
+	if got.UserDN != claims.UserDN || len(got.Groups) != 2 {
+		t.Fatalf("got %+v, want %+v", got, claims)
+	}
+}
+
+func TestSTS_VerifyCredentials_Expired(t *testing.T) {
+	s := &STS{signer: &HMACSigner{Key: []byte("key")}}
+	now := time.Now()
+	claims := &Claims{
+		UserDN:    "cn=alice,dc=example,dc=com",
+		IssuedAt:  now.Add(-2 * time.Hour),
+		ExpiresAt: now.Add(-time.Hour),
+	}
+
+	token, err := s.encode(claims)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if _, err := s.VerifyCredentials(token); !errors.Is(err, ErrInvalidParameter) {
+		t.Fatalf("expected expired credential to be rejected, got %v", err)
+	}
+}
+
+func TestSTS_VerifyCredentials_TamperedTokenFails(t *testing.T) {
+	s := &STS{signer: &HMACSigner{Key: []byte("key")}}
+	now := time.Now()
+	token, err := s.encode(&Claims{UserDN: "cn=alice,dc=example,dc=com", IssuedAt: now, ExpiresAt: now.Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+
+	tampered := token + "x"
+	if _, err := s.VerifyCredentials(tampered); err == nil {
+		t.Fatalf("expected tampered token to fail verification")
+	}
+}
+
+func TestSplitToken_Malformed(t *testing.T) {
+	if _, _, err := splitToken("no-dot-here"); !errors.Is(err, ErrInvalidParameter) {
+		t.Fatalf("expected malformed token error, got %v", err)
+	}
+	if _, _, err := splitToken("not base64!.also not base64!"); !errors.Is(err, ErrInvalidParameter) {
+		t.Fatalf("expected malformed payload error, got %v", err)
+	}
+}